@@ -0,0 +1,99 @@
+//go:build linux && !tinygo
+
+// Command ffbconfig reads and changes the wheel settings over the vendor
+// HID feature reports.
+//
+//	ffbconfig list
+//	ffbconfig set lock2lock=900 viscosity=64
+//	ffbconfig save
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/SWITCHSCIENCE/ffb_steering_controller/console"
+	"github.com/SWITCHSCIENCE/ffb_steering_controller/hidconfig"
+)
+
+var device = flag.String("dev", "", "hidraw device (default: the first wheel found)")
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: ffbconfig [-dev /dev/hidrawN] list | set name=value... | save | calibrate | reload | defaults | version")
+	os.Exit(2)
+}
+
+func open() (*hidconfig.Client, error) {
+	if *device == "" {
+		return hidconfig.Open()
+	}
+	dev, err := hidconfig.OpenHidraw(*device)
+	if err != nil {
+		return nil, err
+	}
+	return hidconfig.New(dev), nil
+}
+
+func main() {
+	flag.Parse()
+	if flag.NArg() == 0 {
+		usage()
+	}
+	c, err := open()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer c.Close()
+	if err := run(c, flag.Arg(0), flag.Args()[1:]); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(c *hidconfig.Client, cmd string, args []string) error {
+	switch cmd {
+	case "list":
+		s, err := c.Settings()
+		if err != nil {
+			return err
+		}
+		for _, name := range console.Names() {
+			v, _ := console.Get(s, name)
+			fmt.Printf("%s=%s\n", name, v)
+		}
+	case "set":
+		s, err := c.Settings()
+		if err != nil {
+			return err
+		}
+		for _, arg := range args {
+			name, value, ok := strings.Cut(arg, "=")
+			if !ok {
+				usage()
+			}
+			if err := console.Set(&s, name, value); err != nil {
+				return err
+			}
+		}
+		return c.SetSettings(s)
+	case "save":
+		return c.Save()
+	case "calibrate":
+		return c.Calibrate()
+	case "reload":
+		return c.Reload()
+	case "defaults":
+		return c.Defaults()
+	case "version":
+		major, minor, patch, err := c.Version()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%d.%d.%d\n", major, minor, patch)
+	default:
+		usage()
+	}
+	return nil
+}
@@ -0,0 +1,294 @@
+//go:build !tinygo
+
+// Command ffbdesc parses pid.Descriptor and checks the report structs and
+// the joystick definitions against it, down to the usage under each
+// struct field. The descriptor bytes are also compared with
+// descriptor.golden, one item per line, so a change to the builder code
+// shows up as a diff of items.
+//
+//	ffbdesc          check, exit 1 on a mismatch
+//	ffbdesc -dump    print the collections and report layouts too
+//	ffbdesc -update cmd/ffbdesc/descriptor.golden  after an intended change
+package main
+
+import (
+	_ "embed"
+	"encoding"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/SWITCHSCIENCE/ffb_steering_controller/control"
+	"github.com/SWITCHSCIENCE/ffb_steering_controller/hiddesc"
+	"github.com/SWITCHSCIENCE/ffb_steering_controller/pid"
+)
+
+var (
+	dump   = flag.Bool("dump", false, "print the parsed descriptor")
+	update = flag.String("update", "", "write the golden listing to this file")
+)
+
+//go:embed descriptor.golden
+var golden string
+
+type report struct {
+	kind   hiddesc.Kind
+	id     pid.ReportID
+	v      encoding.BinaryMarshaler
+	usages map[string][]uint32 // by field, see hiddesc.CheckUsages
+}
+
+// Usages of a page.
+func pidUsages(ids ...uint32) []uint32     { return usages(0x0f, ids) }
+func desktopUsages(ids ...uint32) []uint32 { return usages(0x01, ids) }
+func ordinals(ids ...uint32) []uint32      { return usages(0x0a, ids) }
+func vendorUsages(ids ...uint32) []uint32  { return usages(0xff00, ids) }
+
+func usages(page uint32, ids []uint32) []uint32 {
+	us := make([]uint32, len(ids))
+	for i, id := range ids {
+		us[i] = page<<16 | id
+	}
+	return us
+}
+
+var blockIndex = pidUsages(0x22)
+
+var reports = []report{
+	{hiddesc.Input, 2, &pid.PIDStatusInputData{}, map[string][]uint32{
+		"Status":           pidUsages(0x9f, 0xa0, 0xa4, 0xa5, 0xa6),
+		"EffectBlockIndex": pidUsages(0x94, 0x22),
+	}},
+	{hiddesc.Output, 1, &pid.SetEffectOutputData{}, map[string][]uint32{
+		"EffectBlockIndex":        blockIndex,
+		"EffectType":              pidUsages(0x25),
+		"Duration":                pidUsages(0x50),
+		"TriggerRepeatInterval":   pidUsages(0x54),
+		"SamplePeriod":            pidUsages(0x51),
+		"Gain":                    pidUsages(0x52),
+		"TriggerButton":           pidUsages(0x53),
+		"EnableAxis":              append(desktopUsages(0x30, 0x31), pidUsages(0x56)...),
+		"DirectionX":              ordinals(1),
+		"DirectionY":              ordinals(2),
+		"TypeSpecificBlockOffset": pidUsages(0x58),
+		"StartDelay":              pidUsages(0xa7),
+	}},
+	{hiddesc.Output, 2, &pid.SetEnvelopeOutputData{}, map[string][]uint32{
+		"EffectBlockIndex": blockIndex,
+		"AttackLevel":      pidUsages(0x5b),
+		"FadeLevel":        pidUsages(0x5d),
+		"AttackTime":       pidUsages(0x5c),
+		"FadeTime":         pidUsages(0x5e),
+	}},
+	{hiddesc.Output, 3, &pid.SetConditionOutputData{}, map[string][]uint32{
+		"EffectBlockIndex":     blockIndex,
+		"ParameterBlockOffset": append(pidUsages(0x23), ordinals(1, 2)...),
+		"CpOffset":             pidUsages(0x60),
+		"PositiveCoefficient":  pidUsages(0x61),
+		"NegativeCoefficient":  pidUsages(0x62),
+		"PositiveSaturation":   pidUsages(0x63),
+		"NegativeSaturation":   pidUsages(0x64),
+		"DeadBand":             pidUsages(0x65),
+	}},
+	{hiddesc.Output, 4, &pid.SetPeriodicOutputData{}, map[string][]uint32{
+		"EffectBlockIndex": blockIndex,
+		"Magnitude":        pidUsages(0x70),
+		"Offset":           pidUsages(0x6f),
+		"Phase":            pidUsages(0x71),
+		"Period":           pidUsages(0x72),
+	}},
+	{hiddesc.Output, 5, &pid.SetConstantForceOutputData{}, map[string][]uint32{
+		"EffectBlockIndex": blockIndex,
+		"Magnitude":        pidUsages(0x70),
+	}},
+	{hiddesc.Output, 6, &pid.SetRampForceOutputData{}, map[string][]uint32{
+		"EffectBlockIndex": blockIndex,
+		"StartMagnitude":   pidUsages(0x75),
+		"EndMagnitude":     pidUsages(0x76),
+	}},
+	{hiddesc.Output, 7, &pid.SetCustomForceDataOutputData{}, map[string][]uint32{
+		"EffectBlockIndex": blockIndex,
+		"DataOffset":       pidUsages(0x6c),
+		"Data":             pidUsages(0x69),
+	}},
+	{hiddesc.Output, 8, &pid.SetDownloadForceSampleOutputData{}, map[string][]uint32{
+		"X": desktopUsages(0x30),
+		"Y": desktopUsages(0x31),
+	}},
+	{hiddesc.Output, 10, &pid.EffectOperationOutputData{}, map[string][]uint32{
+		"EffectBlockIndex": blockIndex,
+		"Operation":        pidUsages(0x78),
+		"LoopCount":        pidUsages(0x7c),
+	}},
+	{hiddesc.Output, 11, &pid.BlockFreeOutputData{}, map[string][]uint32{
+		"EffectBlockIndex": blockIndex,
+	}},
+	{hiddesc.Output, 12, &pid.DeviceControlOutputData{}, map[string][]uint32{
+		"Control": pidUsages(0x96),
+	}},
+	{hiddesc.Output, 13, &pid.DeviceGainOutputData{}, map[string][]uint32{
+		"Gain": pidUsages(0x7e),
+	}},
+	{hiddesc.Output, 14, &pid.SetCustomForceOutputData{}, map[string][]uint32{
+		"EffectBlockIndex": blockIndex,
+		"SampleCount":      pidUsages(0x6d),
+		"SamplePeriod":     pidUsages(0x51),
+	}},
+	{hiddesc.Feature, 5, &pid.CreateNewEffectFeatureData{}, map[string][]uint32{
+		"EffectType": pidUsages(0x25),
+		"ByteCount":  desktopUsages(0x3b),
+	}},
+	{hiddesc.Feature, 6, &pid.PIDBlockLoadFeatureData{}, map[string][]uint32{
+		"EffectBlockIndex": blockIndex,
+		"LoadStatus":       pidUsages(0x8b),
+		"RamPoolAvailable": pidUsages(0xac),
+	}},
+	{hiddesc.Feature, 7, &pid.PIDPoolFeatureData{}, map[string][]uint32{
+		"RamPoolSize":            pidUsages(0x80),
+		"MaxSimultaneousEffects": pidUsages(0x83),
+		"MemoryManagement":       pidUsages(0xa9, 0xaa),
+	}},
+	{hiddesc.Feature, pid.ReportConfigSettings, &pid.ConfigSettingsFeatureData{}, map[string][]uint32{
+		"Settings": vendorUsages(uint32(pid.ReportConfigSettings)),
+	}},
+	{hiddesc.Feature, pid.ReportConfigCommand, &pid.ConfigCommandFeatureData{}, map[string][]uint32{
+		"Command": vendorUsages(uint32(pid.ReportConfigCommand)),
+		"Status":  vendorUsages(uint32(pid.ReportConfigCommand)),
+		"Version": vendorUsages(uint32(pid.ReportConfigCommand)),
+	}},
+	{hiddesc.Feature, pid.ReportConfigWrite, &pid.ConfigWriteFeatureData{}, map[string][]uint32{
+		"Offset": vendorUsages(uint32(pid.ReportConfigWrite)),
+		"Data":   vendorUsages(uint32(pid.ReportConfigWrite)),
+	}},
+}
+
+func main() {
+	flag.Parse()
+	d, err := hiddesc.Parse(pid.Descriptor)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *dump {
+		d.Dump(os.Stdout)
+	}
+	listing, err := itemListing(pid.Descriptor)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *update != "" {
+		if err := os.WriteFile(*update, []byte(listing), 0644); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	failed := false
+	if err := compareGolden(listing); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		failed = true
+	}
+	for _, err := range check(d) {
+		fmt.Fprintln(os.Stderr, err)
+		failed = true
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func check(d *hiddesc.Descriptor) []error {
+	var errs []error
+	seen := map[*hiddesc.Report]bool{}
+	for _, r := range reports {
+		dr := d.Report(r.kind, uint8(r.id))
+		if dr == nil {
+			errs = append(errs, fmt.Errorf("%T: no %s report %d in the descriptor", r.v, r.kind, r.id))
+			continue
+		}
+		seen[dr] = true
+		if err := hiddesc.Check(dr, r.v); err != nil {
+			errs = append(errs, err)
+		}
+		if err := hiddesc.CheckUsages(dr, r.v, r.usages); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := checkJoystick(d); err != nil {
+		errs = append(errs, err)
+	} else {
+		seen[d.Report(hiddesc.Input, 1)] = true
+	}
+	for _, r := range d.Reports {
+		if !seen[r] {
+			errs = append(errs, fmt.Errorf("%s report %d has no struct", r.Kind, r.ID))
+		}
+	}
+	return errs
+}
+
+// checkJoystick compares input report 1 with control.JoystickButtons and
+// control.JoystickAxes.
+func checkJoystick(d *hiddesc.Descriptor) error {
+	r := d.Report(hiddesc.Input, 1)
+	if r == nil {
+		return fmt.Errorf("no joystick input report 1 in the descriptor")
+	}
+	buttons := 0
+	var axes []control.AxisRange
+	for _, f := range r.Fields {
+		if f.Constant() {
+			continue
+		}
+		if f.Usage(0)>>16 == 0x09 {
+			buttons += f.Count
+			continue
+		}
+		for i := 0; i < f.Count; i++ {
+			axes = append(axes, control.AxisRange{Min: int(f.LogicalMin), Max: int(f.LogicalMax)})
+		}
+	}
+	if buttons != control.JoystickButtons {
+		return fmt.Errorf("joystick has %d buttons, descriptor %d", control.JoystickButtons, buttons)
+	}
+	if len(axes) != len(control.JoystickAxes) {
+		return fmt.Errorf("joystick has %d axes, descriptor %d", len(control.JoystickAxes), len(axes))
+	}
+	for i, a := range axes {
+		if a != control.JoystickAxes[i] {
+			return fmt.Errorf("joystick axis %d is %v, descriptor %v", i, control.JoystickAxes[i], a)
+		}
+	}
+	return nil
+}
+
+// itemListing writes the bytes of each item on a line.
+func itemListing(desc []byte) (string, error) {
+	items, err := hiddesc.Items(desc)
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	for _, it := range items {
+		fmt.Fprintf(&sb, "% x\n", desc[it.Offset:it.Offset+1+len(it.Data)])
+	}
+	return sb.String(), nil
+}
+
+func compareGolden(listing string) error {
+	got := strings.Split(listing, "\n")
+	want := strings.Split(golden, "\n")
+	for i := 0; i < len(got) || i < len(want); i++ {
+		var g, w string
+		if i < len(got) {
+			g = got[i]
+		}
+		if i < len(want) {
+			w = want[i]
+		}
+		if g != w {
+			return fmt.Errorf("descriptor item %d is %q, descriptor.golden has %q", i, g, w)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+//go:build !tinygo
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/SWITCHSCIENCE/ffb_steering_controller/hiddesc"
+	"github.com/SWITCHSCIENCE/ffb_steering_controller/pid"
+)
+
+// TestGolden pins pid.Descriptor byte for byte. After an intended layout
+// change run: go run ./cmd/ffbdesc -update cmd/ffbdesc/descriptor.golden
+func TestGolden(t *testing.T) {
+	listing, err := itemListing(pid.Descriptor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := compareGolden(listing); err != nil {
+		t.Error(err)
+	}
+	// a changed, added or removed item is reported
+	lines := strings.SplitAfter(listing, "\n")
+	for _, changed := range []string{
+		strings.Replace(listing, lines[0], "05 02\n", 1),
+		listing + "c0\n",
+		strings.Join(lines[:len(lines)-2], ""),
+	} {
+		if compareGolden(changed) == nil {
+			t.Errorf("no mismatch for a changed listing")
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	d, err := hiddesc.Parse(pid.Descriptor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, err := range check(d) {
+		t.Error(err)
+	}
+
+	// Duration and Trigger Repeat Interval have the same size, so only
+	// the usages tell them apart
+	desc := bytes.Clone(pid.Descriptor)
+	i := bytes.Index(desc, []byte{0x09, 0x50})
+	j := bytes.Index(desc, []byte{0x09, 0x54})
+	if i < 0 || j < 0 {
+		t.Fatal("no Duration or Trigger Repeat Interval usage")
+	}
+	desc[i+1], desc[j+1] = 0x54, 0x50
+	if d, err = hiddesc.Parse(desc); err != nil {
+		t.Fatal(err)
+	}
+	errs := check(d)
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "Duration") {
+		t.Errorf("swapped usages: %v", errs)
+	}
+}
@@ -0,0 +1,111 @@
+//go:build !tinygo
+
+// Command ffbtelemetry decodes the telemetry stream of the wheel to CSV.
+//
+//	ffbtelemetry /dev/ttyACM0 > run.csv
+//	ffbtelemetry -o run.csv capture.bin
+//
+// The source may be the USB serial port of the firmware built with the
+// telemetry tag, a file written by wheeld -telemetry, or - for stdin.
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/SWITCHSCIENCE/ffb_steering_controller/telemetry"
+)
+
+var (
+	output = flag.String("o", "", "write the CSV to this file instead of stdout")
+	limit  = flag.Int("n", 0, "stop after this many samples (0: until EOF or interrupt)")
+)
+
+func open(name string) (io.ReadCloser, error) {
+	if name == "-" {
+		return io.NopCloser(os.Stdin), nil
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := makeRaw(f); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return f, nil
+}
+
+func main() {
+	flag.Parse()
+	if flag.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: ffbtelemetry [-o out.csv] [-n samples] <serial port | file | ->")
+		os.Exit(2)
+	}
+	in, err := open(flag.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer in.Close()
+	out := os.Stdout
+	if *output != "" {
+		if out, err = os.Create(*output); err != nil {
+			log.Fatal(err)
+		}
+		defer out.Close()
+	}
+	bw := bufio.NewWriter(out)
+	defer bw.Flush()
+	if err := convert(csv.NewWriter(bw), telemetry.NewDecoder(in), *limit); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// convert writes a header row for every schema change and a row per sample.
+func convert(w *csv.Writer, dec *telemetry.Decoder, limit int) error {
+	defer w.Flush()
+	var header []string
+	row := []string{}
+	for n := 0; limit == 0 || n < limit; n++ {
+		s, err := dec.Next()
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if names := dec.Names(); !equal(names, header) {
+			header = names
+			if err := w.Write(append([]string{"seq", "time_ms"}, header...)); err != nil {
+				return err
+			}
+		}
+		row = append(row[:0], strconv.Itoa(int(s.Seq)), strconv.FormatUint(uint64(s.Time), 10))
+		for _, v := range s.Values {
+			row = append(row, strconv.Itoa(int(v)))
+		}
+		if err := w.Write(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,10 @@
+//go:build !linux && !tinygo
+
+package main
+
+import "os"
+
+// makeRaw is a no-op: configure the serial port with the OS tools.
+func makeRaw(f *os.File) error {
+	return nil
+}
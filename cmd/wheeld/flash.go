@@ -0,0 +1,63 @@
+//go:build linux && !tinygo
+
+package main
+
+import (
+	"io"
+	"os"
+)
+
+const (
+	fileFlashBlock  = 4096
+	fileFlashBlocks = 2
+)
+
+// fileFlash emulates a small NOR flash in a regular file.
+type fileFlash struct {
+	*os.File
+}
+
+func openFileFlash(name string) (*fileFlash, error) {
+	f, err := os.OpenFile(name, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	ff := &fileFlash{File: f}
+	st, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	if st.Size() < ff.Size() {
+		if err := ff.EraseBlocks(0, fileFlashBlocks); err != nil {
+			f.Close()
+			return nil, err
+		}
+	}
+	return ff, nil
+}
+
+func (f *fileFlash) ReadAt(p []byte, off int64) (int, error) {
+	n, err := f.File.ReadAt(p, off)
+	if err == io.EOF && n == len(p) {
+		err = nil
+	}
+	return n, err
+}
+
+func (f *fileFlash) Size() int64           { return fileFlashBlock * fileFlashBlocks }
+func (f *fileFlash) WriteBlockSize() int64 { return 256 }
+func (f *fileFlash) EraseBlockSize() int64 { return fileFlashBlock }
+
+func (f *fileFlash) EraseBlocks(start, n int64) error {
+	blank := make([]byte, fileFlashBlock)
+	for i := range blank {
+		blank[i] = 0xff
+	}
+	for b := start; b < start+n; b++ {
+		if _, err := f.WriteAt(blank, b*fileFlashBlock); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+//go:build linux && !tinygo
+
+package main
+
+import (
+	"log"
+	"time"
+)
+
+// hostJoystick stands in for the USB HID joystick.
+type hostJoystick struct {
+	verbose bool
+	axes    [6]int
+	last    time.Time
+}
+
+func (j *hostJoystick) SetButton(index int, push bool) {}
+
+func (j *hostJoystick) SetAxis(index int, v int) {
+	if index >= 0 && index < len(j.axes) {
+		j.axes[index] = v
+	}
+}
+
+func (j *hostJoystick) SendState() {
+	if !j.verbose || time.Since(j.last) < 100*time.Millisecond {
+		return
+	}
+	j.last = time.Now()
+	log.Printf("steering: %6d", j.axes[0])
+}
@@ -0,0 +1,100 @@
+//go:build linux && !tinygo
+
+// Command wheeld runs the wheel control loop on a Linux host and talks to
+// the servo over SocketCAN instead of the MCP2515.
+//
+//	wheeld -iface can0 -dump session.log
+//	wheeld -replay session.log
+//	wheeld -replay session.log -telemetry run.bin
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/SWITCHSCIENCE/ffb_steering_controller/can"
+	"github.com/SWITCHSCIENCE/ffb_steering_controller/control"
+	"github.com/SWITCHSCIENCE/ffb_steering_controller/pid"
+	"github.com/SWITCHSCIENCE/ffb_steering_controller/settings"
+)
+
+var (
+	iface    = flag.String("iface", "vcan0", "SocketCAN interface")
+	dump     = flag.String("dump", "", "write all CAN traffic to this candump log")
+	replay   = flag.String("replay", "", "answer servo requests from this candump log instead of the bus")
+	flash    = flag.String("settings", "", "persist settings in this file")
+	verbose  = flag.Bool("v", false, "print the steering axis")
+	tlm      = flag.String("telemetry", "", "write the telemetry stream to this file")
+	tlmEvery = flag.Int("telemetry-every", 10, "ticks between telemetry samples")
+	retryDur = 3 * time.Second
+)
+
+func openBus() (can.Bus, error) {
+	if *replay != "" {
+		return openReplay(*replay)
+	}
+	return can.OpenSocketCAN(*iface)
+}
+
+func main() {
+	flag.Parse()
+	bus, err := openBus()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *dump != "" {
+		f, err := os.Create(*dump)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		bus = can.NewDump(bus, f, *iface)
+	}
+	if *flash != "" {
+		dev, err := openFileFlash(*flash)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer dev.Close()
+		if err := settings.SetStorage(dev); err != nil {
+			log.Fatal(err)
+		}
+	}
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+	w := control.NewWheelWith(bus, &hostJoystick{verbose: *verbose}, pid.NewPIDHandler())
+	if *tlm != "" {
+		f, err := os.Create(*tlm)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		if err := w.SetTelemetry(f, *tlmEvery); err != nil {
+			log.Fatal(err)
+		}
+	}
+	for {
+		err := w.Loop(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if *replay != "" {
+			if errors.Is(err, can.ErrTimeout) {
+				log.Print("replay finished")
+				return
+			}
+			log.Fatal(err)
+		}
+		log.Print(err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(retryDur):
+		}
+	}
+}
@@ -0,0 +1,47 @@
+//go:build linux && !tinygo
+
+package main
+
+import (
+	"os"
+
+	"github.com/SWITCHSCIENCE/ffb_steering_controller/can"
+	"github.com/SWITCHSCIENCE/ffb_steering_controller/motor"
+)
+
+// commands are the IDs sent to the servo; everything else in a log is a reply.
+var commands = map[uint32]bool{
+	motor.CmdCurrent:  true,
+	motor.CmdMode:     true,
+	motor.CmdFeedback: true,
+	motor.CmdQuery:    true,
+	motor.CmdIDQuery:  true,
+}
+
+// openReplay returns a bus that answers each request with the next
+// servo reply recorded in a candump log.
+func openReplay(name string) (can.Bus, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	frames, err := can.ParseDump(f)
+	if err != nil {
+		return nil, err
+	}
+	var replies []can.Frame
+	for _, fr := range frames {
+		if !commands[fr.ID] {
+			replies = append(replies, fr)
+		}
+	}
+	return can.NewLoopback(func(f can.Frame) []can.Frame {
+		if f.ID == motor.CmdCurrent || len(replies) == 0 {
+			return nil
+		}
+		r := replies[0]
+		replies = replies[1:]
+		return []can.Frame{r}
+	}), nil
+}
@@ -1,3 +1,5 @@
+//go:build tinygo
+
 package main
 
 import (
@@ -7,10 +9,19 @@ import (
 
 	"tinygo.org/x/drivers/mcp2515"
 
+	"github.com/SWITCHSCIENCE/ffb_steering_controller/can"
+	"github.com/SWITCHSCIENCE/ffb_steering_controller/console"
 	"github.com/SWITCHSCIENCE/ffb_steering_controller/control"
 	"github.com/SWITCHSCIENCE/ffb_steering_controller/settings"
 )
 
+// firmware version reported to configuration tools
+const (
+	VersionMajor = 0
+	VersionMinor = 1
+	VersionPatch = 0
+)
+
 const (
 	LED1      machine.Pin = 25
 	LED2      machine.Pin = 14
@@ -29,6 +40,17 @@ const (
 var (
 	spi = machine.SPI0
 	sw  [3]bool
+	con = console.New(machine.Serial)
+	// saveIn counts down the update ticks until a changed lock to lock
+	// is written to flash, so stepping through the values saves once.
+	saveIn int
+
+	// setupTelemetry is replaced when built with the telemetry tag.
+	setupTelemetry = func(w *control.Wheel) {}
+	// setupPedals is replaced when built with the pedals tag.
+	setupPedals = func(w *control.Wheel) {}
+	// setupButtons is replaced when built with the buttons tag.
+	setupButtons = func(w *control.Wheel) {}
 )
 
 func init() {
@@ -49,6 +71,21 @@ func init() {
 	time.Sleep(10 * time.Millisecond)
 }
 
+// pollConsole runs the commands received on the USB serial port.
+func pollConsole() {
+	for machine.Serial.Buffered() > 0 {
+		c, err := machine.Serial.ReadByte()
+		if err != nil {
+			return
+		}
+		con.Feed(c)
+	}
+}
+
+// saveDelay is how many 20 ms update ticks the lock to lock must stay
+// unchanged before it is saved.
+const saveDelay = 100
+
 func update() {
 	s := settings.Get()
 	now := [3]bool{
@@ -114,7 +151,18 @@ func update() {
 	}
 	if s.Lock2Lock != next {
 		s.Lock2Lock = next
-		settings.Update(s)
+		if err := settings.Update(s); err == nil {
+			saveIn = saveDelay
+		}
+		return
+	}
+	if saveIn > 0 {
+		saveIn--
+		if saveIn == 0 {
+			if err := settings.Save(s); err != nil {
+				println(err.Error())
+			}
+		}
 	}
 }
 
@@ -131,15 +179,19 @@ func main() {
 	); err != nil {
 		panic(err)
 	}
-	can := mcp2515.New(spi, CAN_CS)
-	can.Configure()
-	if err := can.Begin(mcp2515.CAN500kBps, mcp2515.Clock8MHz); err != nil {
+	dev := mcp2515.New(spi, CAN_CS)
+	dev.Configure()
+	if err := dev.Begin(mcp2515.CAN500kBps, mcp2515.Clock8MHz); err != nil {
 		panic(err)
 	}
-	js := control.NewWheel(can)
-	s := settings.Get()
-	s.MaxCenteringForce = 50
-	settings.Update(s)
+	if err := settings.SetStorage(machine.Flash); err != nil {
+		println(err.Error())
+	}
+	control.SetFirmwareVersion(VersionMajor, VersionMinor, VersionPatch)
+	js := control.NewWheel(can.NewMCP2515(dev, spi, CAN_CS))
+	setupTelemetry(js)
+	setupPedals(js)
+	setupButtons(js)
 	ctx, cancel := context.WithCancel(context.Background())
 	go func() {
 		tick := time.NewTicker(20 * time.Millisecond)
@@ -148,6 +200,7 @@ func main() {
 			case <-ctx.Done():
 				return
 			case <-tick.C:
+				pollConsole()
 				update()
 			}
 		}
@@ -0,0 +1,53 @@
+//go:build tinygo && buttons
+
+package main
+
+import (
+	"machine"
+
+	"github.com/SWITCHSCIENCE/ffb_steering_controller/control"
+	"github.com/SWITCHSCIENCE/ffb_steering_controller/input"
+)
+
+// button wiring: two paddle shifters on their own pins and a rim with
+// 16 buttons behind two 74HC165
+const (
+	PADDLE_UP   machine.Pin = 4
+	PADDLE_DOWN machine.Pin = 5
+	RIM_LOAD    machine.Pin = 6
+	RIM_CLOCK   machine.Pin = 7
+	RIM_DATA    machine.Pin = 8
+	RIM_BUTTONS             = 16
+)
+
+func init() {
+	setupButtons = func(w *control.Wheel) {
+		for _, p := range []machine.Pin{PADDLE_UP, PADDLE_DOWN, RIM_DATA} {
+			p.Configure(machine.PinConfig{Mode: machine.PinInputPullup})
+		}
+		for _, p := range []machine.Pin{RIM_LOAD, RIM_CLOCK} {
+			p.Configure(machine.PinConfig{Mode: machine.PinOutput})
+			p.High()
+		}
+		l := input.NewButtonLayer()
+		l.AddSource(&input.GPIOButtons{Pins: []input.Pin{PADDLE_UP, PADDLE_DOWN}})
+		l.AddSource(&input.ShiftRegister{Load: RIM_LOAD, Clock: RIM_CLOCK, Data: RIM_DATA, Buttons: RIM_BUTTONS})
+		// paddles: joystick buttons 0 and 1
+		l.Bind(0, input.Bind(0))
+		l.Bind(1, input.Bind(1))
+		// rim 0..13: buttons 2..15, with the shift button held rim 0..5
+		// give buttons 18..23
+		for i := 0; i < 14; i++ {
+			b := input.Bind(2 + i)
+			if i < 6 {
+				b.Shifted = 18 + i
+			}
+			l.Bind(2+i, b)
+		}
+		// rim 14: button 16 on a tap, 17 when held
+		l.Bind(2+14, input.Binding{Button: 16, Shifted: input.NoButton, Long: 17})
+		// rim 15: shift
+		l.Bind(2+15, input.Binding{Button: input.NoButton, Shifted: input.NoButton, Long: input.NoButton, Shift: true})
+		w.SetButtons(l)
+	}
+}
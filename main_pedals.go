@@ -0,0 +1,33 @@
+//go:build tinygo && pedals
+
+package main
+
+import (
+	"machine"
+
+	"github.com/SWITCHSCIENCE/ffb_steering_controller/control"
+	"github.com/SWITCHSCIENCE/ffb_steering_controller/input"
+	"github.com/SWITCHSCIENCE/ffb_steering_controller/settings"
+)
+
+// pedal wiring: potentiometers on the ADC pins, the brake load cell on
+// an HX711
+const (
+	THROTTLE_ADC  machine.Pin = 26
+	CLUTCH_ADC    machine.Pin = 27
+	HANDBRAKE_ADC machine.Pin = 28
+	BRAKE_DOUT    machine.Pin = 2
+	BRAKE_SCK     machine.Pin = 3
+)
+
+func init() {
+	setupPedals = func(w *control.Wheel) {
+		machine.InitADC()
+		p := input.NewPedals()
+		p.Attach(settings.PedalThrottle, input.NewADC(THROTTLE_ADC))
+		p.Attach(settings.PedalClutch, input.NewADC(CLUTCH_ADC))
+		p.Attach(settings.PedalHandbrake, input.NewADC(HANDBRAKE_ADC))
+		p.Attach(settings.PedalBrake, input.NewHX711(BRAKE_DOUT, BRAKE_SCK))
+		w.SetPedals(p)
+	}
+}
@@ -0,0 +1,36 @@
+//go:build tinygo && telemetry
+
+package main
+
+import (
+	"fmt"
+	"machine"
+	"strconv"
+
+	"github.com/SWITCHSCIENCE/ffb_steering_controller/control"
+	"github.com/SWITCHSCIENCE/ffb_steering_controller/telemetry"
+)
+
+// telemetryInterval is the number of 1ms ticks between samples at start.
+// The console command "telemetry <ticks>" changes it, 0 stops the stream.
+const telemetryInterval = 10
+
+// telemetryQueue holds the frames until the serial port takes them, so
+// the control loop drops samples instead of waiting for the host.
+var telemetryQueue = telemetry.NewQueue(1024)
+
+func init() {
+	setupTelemetry = func(w *control.Wheel) {
+		go telemetryQueue.WriteTo(machine.Serial)
+		if err := w.SetTelemetry(telemetryQueue, telemetryInterval); err != nil {
+			println(err.Error())
+		}
+		con.Handle("telemetry", 1, "telemetry <ticks>", func(args []string) error {
+			n, err := strconv.Atoi(args[0])
+			if err != nil || n < 0 {
+				return fmt.Errorf("invalid ticks: %q", args[0])
+			}
+			return w.SetTelemetry(telemetryQueue, n)
+		})
+	}
+}